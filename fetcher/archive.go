@@ -0,0 +1,139 @@
+package fetcher
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"runtime"
+	"strings"
+)
+
+// ArchiveFile is a single entry inside an extracted archive, abstracting
+// over zip.File and tar.Header so an Extract func can work with either
+// format uniformly.
+type ArchiveFile struct {
+	Name string
+	Mode fs.FileMode
+	open func() (io.ReadCloser, error)
+}
+
+// Open returns a reader for this entry's contents.
+func (f ArchiveFile) Open() (io.ReadCloser, error) {
+	return f.open()
+}
+
+// Extract selects a single file from within an archive's entries, run after
+// Match has picked the archive asset itself.
+type Extract func(files []ArchiveFile) (io.Reader, error)
+
+// openArchive reads data as a zip or tar.gz archive, detected by magic
+// bytes, and returns its entries for an Extract func to pick from.
+func openArchive(data []byte) ([]ArchiveFile, error) {
+	switch {
+	case len(data) >= 2 && data[0] == 'P' && data[1] == 'K':
+		return openZip(data)
+	case len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b:
+		return openTarGz(data)
+	default:
+		return nil, errors.New("unrecognized archive format")
+	}
+}
+
+func openZip(data []byte) ([]ArchiveFile, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read zip archive: %w", err)
+	}
+	return archiveFilesFromZip(reader), nil
+}
+
+func archiveFilesFromZip(reader *zip.Reader) []ArchiveFile {
+	files := make([]ArchiveFile, 0, len(reader.File))
+	for _, zf := range reader.File {
+		zf := zf
+		files = append(files, ArchiveFile{
+			Name: zf.Name,
+			Mode: zf.Mode(),
+			open: func() (io.ReadCloser, error) { return zf.Open() },
+		})
+	}
+	return files
+}
+
+func openTarGz(data []byte) ([]ArchiveFile, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gzip stream: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	var files []ArchiveFile
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		content := make([]byte, hdr.Size)
+		if _, err := io.ReadFull(tr, content); err != nil {
+			return nil, fmt.Errorf("failed to read tar entry %s: %w", hdr.Name, err)
+		}
+		files = append(files, ArchiveFile{
+			Name: hdr.Name,
+			Mode: hdr.FileInfo().Mode(),
+			open: func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(content)), nil },
+		})
+	}
+	return files, nil
+}
+
+// ExtractByName returns an Extract that picks the entry whose path matches
+// exactly, e.g. "bin/mytool" inside a tar.gz.
+func ExtractByName(path string) Extract {
+	return func(files []ArchiveFile) (io.Reader, error) {
+		for _, f := range files {
+			if f.Name == path {
+				return f.Open()
+			}
+		}
+		return nil, fmt.Errorf("archive entry %q not found", path)
+	}
+}
+
+// ExtractByGOOSGOARCH returns an Extract that picks the first entry whose
+// name contains both GOOS and GOARCH.
+func ExtractByGOOSGOARCH() Extract {
+	return func(files []ArchiveFile) (io.Reader, error) {
+		for _, f := range files {
+			if strings.Contains(f.Name, runtime.GOOS) && strings.Contains(f.Name, runtime.GOARCH) {
+				return f.Open()
+			}
+		}
+		return nil, errors.New("no archive entry matches GOOS/GOARCH")
+	}
+}
+
+// ExtractFirstExecutable returns an Extract that picks the first regular
+// file with any executable bit set, mirroring how tools like restic's
+// selfupdate locate their binary inside a release tarball.
+func ExtractFirstExecutable() Extract {
+	return func(files []ArchiveFile) (io.Reader, error) {
+		for _, f := range files {
+			if f.Mode&0o111 != 0 {
+				return f.Open()
+			}
+		}
+		return nil, errors.New("no executable entry found in archive")
+	}
+}
@@ -0,0 +1,171 @@
+package fetcher
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"runtime"
+	"testing"
+)
+
+func buildZip(t *testing.T, files map[string]fs.FileMode) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, mode := range files {
+		hdr := &zip.FileHeader{Name: name}
+		hdr.SetMode(mode)
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			t.Fatalf("failed to create zip entry %s: %v", name, err)
+		}
+		if _, err := w.Write([]byte("content of " + name)); err != nil {
+			t.Fatalf("failed to write zip entry %s: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close zip writer: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func buildTarGz(t *testing.T, files map[string]fs.FileMode) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for name, mode := range files {
+		content := []byte("content of " + name)
+		hdr := &tar.Header{
+			Name: name,
+			Mode: int64(mode),
+			Size: int64(len(content)),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("failed to write tar header for %s: %v", name, err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			t.Fatalf("failed to write tar entry %s: %v", name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close tar writer: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("failed to close gzip writer: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestOpenArchive(t *testing.T) {
+	zipData := buildZip(t, map[string]fs.FileMode{"tool": 0o755})
+	tarGzData := buildTarGz(t, map[string]fs.FileMode{"tool": 0o755})
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantLen int
+		wantErr bool
+	}{
+		{"zip", zipData, 1, false},
+		{"tar.gz", tarGzData, 1, false},
+		{"unrecognized", []byte("not an archive"), 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := openArchive(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("openArchive() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && len(files) != tt.wantLen {
+				t.Errorf("openArchive() returned %d files, want %d", len(files), tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestExtractByName(t *testing.T) {
+	data := buildTarGz(t, map[string]fs.FileMode{
+		"LICENSE": 0o644,
+		"bin/app": 0o755,
+	})
+	files, err := openArchive(data)
+	if err != nil {
+		t.Fatalf("openArchive() failed: %v", err)
+	}
+
+	r, err := ExtractByName("bin/app")(files)
+	if err != nil {
+		t.Fatalf("ExtractByName() failed: %v", err)
+	}
+	content, _ := io.ReadAll(r)
+	if string(content) != "content of bin/app" {
+		t.Errorf("ExtractByName() content = %q", content)
+	}
+
+	if _, err := ExtractByName("missing")(files); err == nil {
+		t.Error("ExtractByName() with missing entry should error")
+	}
+}
+
+func TestExtractByGOOSGOARCH(t *testing.T) {
+	match := fmt.Sprintf("tool_%s_%s", runtime.GOOS, runtime.GOARCH)
+	data := buildZip(t, map[string]fs.FileMode{
+		"tool_other_other": 0o755,
+		match:              0o755,
+	})
+	files, err := openArchive(data)
+	if err != nil {
+		t.Fatalf("openArchive() failed: %v", err)
+	}
+
+	r, err := ExtractByGOOSGOARCH()(files)
+	if err != nil {
+		t.Fatalf("ExtractByGOOSGOARCH() failed: %v", err)
+	}
+	content, _ := io.ReadAll(r)
+	if string(content) != "content of "+match {
+		t.Errorf("ExtractByGOOSGOARCH() content = %q", content)
+	}
+
+	onlyOther, err := openArchive(buildZip(t, map[string]fs.FileMode{"tool_other_other": 0o755}))
+	if err != nil {
+		t.Fatalf("openArchive() failed: %v", err)
+	}
+	if _, err := ExtractByGOOSGOARCH()(onlyOther); err == nil {
+		t.Error("ExtractByGOOSGOARCH() with no matching entry should error")
+	}
+}
+
+func TestExtractFirstExecutable(t *testing.T) {
+	data := buildTarGz(t, map[string]fs.FileMode{
+		"README.md": 0o644,
+		"app":       0o755,
+	})
+	files, err := openArchive(data)
+	if err != nil {
+		t.Fatalf("openArchive() failed: %v", err)
+	}
+
+	r, err := ExtractFirstExecutable()(files)
+	if err != nil {
+		t.Fatalf("ExtractFirstExecutable() failed: %v", err)
+	}
+	content, _ := io.ReadAll(r)
+	if string(content) != "content of app" {
+		t.Errorf("ExtractFirstExecutable() content = %q", content)
+	}
+
+	noExec, err := openArchive(buildTarGz(t, map[string]fs.FileMode{"README.md": 0o644}))
+	if err != nil {
+		t.Fatalf("openArchive() failed: %v", err)
+	}
+	if _, err := ExtractFirstExecutable()(noExec); err == nil {
+		t.Error("ExtractFirstExecutable() with no executable entry should error")
+	}
+}
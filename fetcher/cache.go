@@ -0,0 +1,122 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// CacheEntry holds the conditional-request metadata for a single cached
+// endpoint.
+type CacheEntry struct {
+	ETag string `json:"etag"`
+}
+
+// Cache stores CacheEntry values keyed by endpoint so repeated Fetch calls
+// can issue conditional requests instead of burning a full API call.
+type Cache interface {
+	Load(key string) (CacheEntry, bool)
+	Save(key string, entry CacheEntry) error
+}
+
+// memoryCache is the default Cache: conditional requests are only avoided
+// within the lifetime of the process.
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string]CacheEntry
+}
+
+// NewMemoryCache returns a Cache that keeps entries in memory only.
+func NewMemoryCache() Cache {
+	return &memoryCache{entries: make(map[string]CacheEntry)}
+}
+
+func (c *memoryCache) Load(key string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	return entry, ok
+}
+
+func (c *memoryCache) Save(key string, entry CacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+	return nil
+}
+
+// FileCache persists entries under $XDG_CACHE_HOME/overseer/<user>-<repo>.json
+// so conditional requests survive process restarts.
+type FileCache struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileCache returns a Cache backed by a JSON file for the given
+// user/repo, creating its parent directory if necessary.
+func NewFileCache(user, repo string) (*FileCache, error) {
+	dir := filepath.Join(cacheHome(), "overseer")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache dir: %w", err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.json", user, repo))
+	return &FileCache{path: path}, nil
+}
+
+func (c *FileCache) Load(key string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries, err := c.readAll()
+	if err != nil {
+		return CacheEntry{}, false
+	}
+	entry, ok := entries[key]
+	return entry, ok
+}
+
+func (c *FileCache) Save(key string, entry CacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries, err := c.readAll()
+	if err != nil {
+		entries = map[string]CacheEntry{}
+	}
+	entries[key] = entry
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	return nil
+}
+
+func (c *FileCache) readAll() (map[string]CacheEntry, error) {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]CacheEntry{}, nil
+		}
+		return nil, err
+	}
+	entries := map[string]CacheEntry{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse cache file: %w", err)
+	}
+	return entries, nil
+}
+
+func cacheHome() string {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".cache"
+	}
+	return filepath.Join(home, ".cache")
+}
@@ -0,0 +1,123 @@
+package fetcher
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v64/github"
+)
+
+// semver is a minimal parsed form of a "vMAJOR.MINOR.PATCH[-PRERELEASE]"
+// tag, enough to order releases without pulling in a full semver package.
+type semver struct {
+	major, minor, patch int
+	prerelease          string
+}
+
+var semverPattern = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z.-]+))?$`)
+
+func parseSemver(tag string) (semver, error) {
+	m := semverPattern.FindStringSubmatch(tag)
+	if m == nil {
+		return semver{}, fmt.Errorf("%q is not a semver tag", tag)
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	patch, _ := strconv.Atoi(m[3])
+	return semver{major: major, minor: minor, patch: patch, prerelease: m[4]}, nil
+}
+
+// less reports whether s sorts before o: a prerelease suffix sorts before
+// the same major.minor.patch without one, matching semver 2.0 precedence.
+func (s semver) less(o semver) bool {
+	if s.major != o.major {
+		return s.major < o.major
+	}
+	if s.minor != o.minor {
+		return s.minor < o.minor
+	}
+	if s.patch != o.patch {
+		return s.patch < o.patch
+	}
+	if s.prerelease == o.prerelease {
+		return false
+	}
+	if s.prerelease == "" {
+		return false
+	}
+	if o.prerelease == "" {
+		return true
+	}
+	return lessPrerelease(s.prerelease, o.prerelease)
+}
+
+// lessPrerelease compares two dot-separated prerelease strings per semver
+// 2.0 rule 11: identifiers are compared left to right, numeric identifiers
+// are compared numerically (so "9" sorts before "10"), and a prerelease with
+// fewer identifiers sorts before one that has all the same leading
+// identifiers plus more.
+func lessPrerelease(a, b string) bool {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		if aParts[i] == bParts[i] {
+			continue
+		}
+		aNum, aIsNum := toUint(aParts[i])
+		bNum, bIsNum := toUint(bParts[i])
+		switch {
+		case aIsNum && bIsNum:
+			return aNum < bNum
+		case aIsNum:
+			return true
+		case bIsNum:
+			return false
+		default:
+			return aParts[i] < bParts[i]
+		}
+	}
+	return len(aParts) < len(bParts)
+}
+
+func toUint(s string) (uint64, bool) {
+	n, err := strconv.ParseUint(s, 10, 64)
+	return n, err == nil
+}
+
+// selectChannelRelease picks the newest release in releases that satisfies
+// h's channel configuration (IncludePrereleases, IncludeDrafts, TagPattern,
+// MinVersion), ordered by semver. MinVersion is parsed once in Init, not
+// here, so a malformed value fails fast at startup instead of every poll.
+func (h *Github) selectChannelRelease(releases []*github.RepositoryRelease) (*github.RepositoryRelease, error) {
+	var best *github.RepositoryRelease
+	var bestVer semver
+	for _, release := range releases {
+		if release.GetDraft() && !h.IncludeDrafts {
+			continue
+		}
+		if release.GetPrerelease() && !h.IncludePrereleases {
+			continue
+		}
+		if h.tagPattern != nil && !h.tagPattern.MatchString(release.GetTagName()) {
+			continue
+		}
+		v, err := parseSemver(release.GetTagName())
+		if err != nil {
+			continue
+		}
+		if h.minVersion != nil && v.less(*h.minVersion) {
+			continue
+		}
+		if best == nil || bestVer.less(v) {
+			best = release
+			bestVer = v
+		}
+	}
+	if best == nil {
+		return nil, errors.New("no release matches the configured channel")
+	}
+	return best, nil
+}
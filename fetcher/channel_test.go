@@ -0,0 +1,145 @@
+package fetcher
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-github/v64/github"
+)
+
+func TestParseSemver(t *testing.T) {
+	tests := []struct {
+		tag     string
+		want    semver
+		wantErr bool
+	}{
+		{"v1.2.3", semver{1, 2, 3, ""}, false},
+		{"1.2.3", semver{1, 2, 3, ""}, false},
+		{"v1.2.3-rc.1", semver{1, 2, 3, "rc.1"}, false},
+		{"not-a-version", semver{}, true},
+		{"v1.2", semver{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, err := parseSemver(tt.tag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSemver(%q) error = %v, wantErr %v", tt.tag, err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("parseSemver(%q) = %+v, want %+v", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSemverLess(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b semver
+		want bool
+	}{
+		{"major", semver{major: 1}, semver{major: 2}, true},
+		{"minor", semver{major: 1, minor: 1}, semver{major: 1, minor: 2}, true},
+		{"patch", semver{major: 1, minor: 1, patch: 1}, semver{major: 1, minor: 1, patch: 2}, true},
+		{"equal", semver{major: 1, minor: 2, patch: 3}, semver{major: 1, minor: 2, patch: 3}, false},
+		{"prerelease before release", semver{major: 1, prerelease: "rc.1"}, semver{major: 1}, true},
+		{"release not before prerelease", semver{major: 1}, semver{major: 1, prerelease: "rc.1"}, false},
+		{"prerelease ordering", semver{major: 1, prerelease: "alpha"}, semver{major: 1, prerelease: "beta"}, true},
+		{"numeric prerelease identifiers compare numerically", semver{major: 1, prerelease: "rc.9"}, semver{major: 1, prerelease: "rc.10"}, true},
+		{"numeric identifier sorts before non-numeric", semver{major: 1, prerelease: "rc.1"}, semver{major: 1, prerelease: "rc.alpha"}, true},
+		{"fewer identifiers sorts before more of the same prefix", semver{major: 1, prerelease: "rc"}, semver{major: 1, prerelease: "rc.1"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.less(tt.b); got != tt.want {
+				t.Errorf("%+v.less(%+v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func release(tag string, draft, prerelease bool) *github.RepositoryRelease {
+	return &github.RepositoryRelease{
+		TagName:    github.String(tag),
+		Draft:      github.Bool(draft),
+		Prerelease: github.Bool(prerelease),
+	}
+}
+
+func TestSelectChannelRelease(t *testing.T) {
+	releases := []*github.RepositoryRelease{
+		release("v1.0.0", false, false),
+		release("v1.2.0", false, false),
+		release("v1.3.0-rc.1", false, true),
+		release("v2.0.0", true, false),
+		release("not-semver", false, false),
+	}
+
+	t.Run("default channel picks newest stable release", func(t *testing.T) {
+		h := &Github{}
+		got, err := h.selectChannelRelease(releases)
+		if err != nil {
+			t.Fatalf("selectChannelRelease() failed: %v", err)
+		}
+		if got.GetTagName() != "v1.2.0" {
+			t.Errorf("selectChannelRelease() = %s, want v1.2.0", got.GetTagName())
+		}
+	})
+
+	t.Run("IncludePrereleases allows the prerelease tip", func(t *testing.T) {
+		h := &Github{IncludePrereleases: true}
+		got, err := h.selectChannelRelease(releases)
+		if err != nil {
+			t.Fatalf("selectChannelRelease() failed: %v", err)
+		}
+		if got.GetTagName() != "v1.3.0-rc.1" {
+			t.Errorf("selectChannelRelease() = %s, want v1.3.0-rc.1", got.GetTagName())
+		}
+	})
+
+	t.Run("IncludeDrafts allows the draft release", func(t *testing.T) {
+		h := &Github{IncludeDrafts: true}
+		got, err := h.selectChannelRelease(releases)
+		if err != nil {
+			t.Fatalf("selectChannelRelease() failed: %v", err)
+		}
+		if got.GetTagName() != "v2.0.0" {
+			t.Errorf("selectChannelRelease() = %s, want v2.0.0", got.GetTagName())
+		}
+	})
+
+	t.Run("TagPattern restricts the candidate set", func(t *testing.T) {
+		h := &Github{tagPattern: regexp.MustCompile(`^v1\.0`)}
+		got, err := h.selectChannelRelease(releases)
+		if err != nil {
+			t.Fatalf("selectChannelRelease() failed: %v", err)
+		}
+		if got.GetTagName() != "v1.0.0" {
+			t.Errorf("selectChannelRelease() = %s, want v1.0.0", got.GetTagName())
+		}
+	})
+
+	t.Run("MinVersion rejects older releases", func(t *testing.T) {
+		min, err := parseSemver("v1.2.0")
+		if err != nil {
+			t.Fatalf("parseSemver() failed: %v", err)
+		}
+		h := &Github{minVersion: &min}
+		got, err := h.selectChannelRelease(releases)
+		if err != nil {
+			t.Fatalf("selectChannelRelease() failed: %v", err)
+		}
+		if got.GetTagName() != "v1.2.0" {
+			t.Errorf("selectChannelRelease() = %s, want v1.2.0", got.GetTagName())
+		}
+	})
+
+	t.Run("no candidates is an error", func(t *testing.T) {
+		h := &Github{tagPattern: regexp.MustCompile(`^v9`)}
+		if _, err := h.selectChannelRelease(releases); err == nil {
+			t.Error("selectChannelRelease() with no matching releases should error")
+		}
+	})
+}
@@ -0,0 +1,15 @@
+package fetcher
+
+import "io"
+
+// Fetcher polls a release or artifact source for a binary matching some
+// criteria and returns it as a stream for overseer to swap in. Init is
+// called once before the first Fetch; Fetch is called repeatedly, blocking
+// for Interval between attempts after the first.
+type Fetcher interface {
+	// Init validates configuration and prepares any clients Fetch needs.
+	Init() error
+	// Fetch returns the next matching asset, or an error if none is
+	// available yet (e.g. no new release, or rate limited).
+	Fetch() (io.Reader, error)
+}
@@ -0,0 +1,133 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+var _ Fetcher = (*Gitea)(nil)
+
+// Gitea uses the Gitea V1 API to retrieve the latest release of a given
+// repository and enumerate its assets. Gitea's release JSON mirrors Github's
+// shape closely (a holdover from its Gogs heritage), so the request and
+// response handling looks a lot like Github's.
+type Gitea struct {
+	// BaseURL is the instance root, e.g. https://gitea.example.com.
+	BaseURL string
+	// Gitea username/org and repository name
+	User, Repo string
+	// Token is optional for authenticated requests (private repos)
+	Token string
+	// Interval between fetches
+	Interval time.Duration
+	// Match is used to find matching release asset.
+	// By default a file will match if it contains both GOOS and GOARCH.
+	Match   func(filename string) bool
+	Context context.Context
+	// internal state
+	delay         bool
+	latestRelease time.Time
+	httpClient    *http.Client
+}
+
+type giteaRelease struct {
+	TagName     string    `json:"tag_name"`
+	PublishedAt time.Time `json:"published_at"`
+	Assets      []struct {
+		Name               string `json:"name"`
+		BrowserDownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// Init validates the provided config
+func (g *Gitea) Init() error {
+	if g.BaseURL == "" {
+		return errors.New("base URL required")
+	}
+	if g.User == "" {
+		return errors.New("user required")
+	}
+	if g.Repo == "" {
+		return errors.New("repo required")
+	}
+	if g.Match == nil {
+		g.Match = DefaultAsset
+	}
+	if g.Interval < time.Minute {
+		g.Interval = time.Minute
+	}
+	if g.Context == nil {
+		g.Context = context.Background()
+	}
+	g.httpClient = &http.Client{Timeout: time.Minute}
+	return nil
+}
+
+// Fetch the binary from the provided Repository
+func (g *Gitea) Fetch() (io.Reader, error) {
+	if g.delay {
+		time.Sleep(g.Interval)
+	}
+	g.delay = true
+
+	return g.fetchLatestRelease()
+}
+
+func (g *Gitea) fetchLatestRelease() (io.Reader, error) {
+	reqURL := fmt.Sprintf("%s/api/v1/repos/%s/%s/releases/latest", g.BaseURL, g.User, g.Repo)
+
+	req, err := http.NewRequestWithContext(g.Context, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	if g.Token != "" {
+		req.Header.Set("Authorization", "token "+g.Token)
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last release: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get last release: %s", resp.Status)
+	}
+
+	var release giteaRelease
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to decode release: %w", err)
+	}
+
+	if g.latestRelease.Equal(release.PublishedAt) {
+		return nil, errors.New("no new release")
+	}
+
+	for _, asset := range release.Assets {
+		if g.Match(asset.Name) {
+			assetReq, err := http.NewRequestWithContext(g.Context, http.MethodGet, asset.BrowserDownloadURL, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build asset request: %w", err)
+			}
+			if g.Token != "" {
+				assetReq.Header.Set("Authorization", "token "+g.Token)
+			}
+			assetResp, err := g.httpClient.Do(assetReq)
+			if err != nil {
+				return nil, fmt.Errorf("failed to download release asset: %w", err)
+			}
+			if assetResp.StatusCode != http.StatusOK {
+				assetResp.Body.Close()
+				return nil, fmt.Errorf("failed to download release asset: %s", assetResp.Status)
+			}
+			g.latestRelease = release.PublishedAt
+			return assetResp.Body, nil
+		}
+	}
+	return nil, nil
+}
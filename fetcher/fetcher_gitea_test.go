@@ -0,0 +1,68 @@
+package fetcher
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGiteaFetchLatestRelease(t *testing.T) {
+	var assetServerURL string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/repos/owner/repo/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{
+			"tag_name": "v1.0.0",
+			"published_at": "2026-01-01T00:00:00Z",
+			"assets": [{"name": "tool_linux_amd64", "browser_download_url": "`+assetServerURL+`/download"}]
+		}`)
+	})
+	mux.HandleFunc("/download", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "binary contents")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	assetServerURL = server.URL
+
+	g := &Gitea{
+		BaseURL: server.URL,
+		User:    "owner",
+		Repo:    "repo",
+		Match:   func(name string) bool { return name == "tool_linux_amd64" },
+	}
+	if err := g.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	r, err := g.fetchLatestRelease()
+	if err != nil {
+		t.Fatalf("fetchLatestRelease() failed: %v", err)
+	}
+	body, _ := io.ReadAll(r)
+	if string(body) != "binary contents" {
+		t.Errorf("fetchLatestRelease() body = %q", body)
+	}
+
+	if _, err := g.fetchLatestRelease(); err == nil {
+		t.Error("fetchLatestRelease() with an unchanged release should error")
+	}
+}
+
+func TestGiteaFetchLatestReleaseErrorStatus(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/repos/owner/repo/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	g := &Gitea{BaseURL: server.URL, User: "owner", Repo: "repo"}
+	if err := g.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	if _, err := g.fetchLatestRelease(); err == nil {
+		t.Error("fetchLatestRelease() with a 404 should error")
+	}
+}
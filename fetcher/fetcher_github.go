@@ -4,17 +4,24 @@ import (
 	"archive/zip"
 	"bytes"
 	"context"
+	"crypto/ed25519"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"runtime"
-	"strings"
+	"net/url"
+	"path"
+	"regexp"
 	"time"
 
 	"github.com/google/go-github/v64/github"
 )
 
+// ErrRateLimited is returned by Fetch when the Github API has throttled
+// requests. Callers should treat this as a signal to skip the current cycle
+// rather than a fatal error.
+var ErrRateLimited = errors.New("github: rate limited")
+
 // Github uses the Github V3 API to retrieve the latest release of a given repository and enumerate its assets. If a release
 // contains a matching asset, it will fetch and return its io.Reader stream.
 type Github struct {
@@ -30,17 +37,69 @@ type Github struct {
 	Context context.Context
 	// Fetch latest artifact instead of release
 	Artifact bool
+	// MaxBackoff caps the delay computed from a rate limit response. Zero
+	// means the delay reported by the API is used as-is.
+	MaxBackoff time.Duration
+	// RateLimitCallback, if set, is invoked whenever Fetch is throttled by
+	// the Github API, with the duration it is about to sleep for.
+	RateLimitCallback func(retryAfter time.Duration)
+	// Cache stores ETags for conditional requests so unchanged releases and
+	// workflow runs don't count against the primary rate limit. Defaults to
+	// an in-memory cache; pass a *FileCache to persist across restarts.
+	Cache Cache
+	// ChecksumAsset, if set, identifies a companion checksums file (e.g.
+	// SHA256SUMS or checksums.txt) whose contents are parsed to find the
+	// expected digest for the matched binary.
+	ChecksumAsset func(filename string) bool
+	// SignatureAsset, if set, identifies a companion detached signature
+	// asset used to verify the matched binary via Verifier.
+	SignatureAsset func(filename string) bool
+	// PublicKey builds a default Ed25519Verifier when SignatureAsset is set
+	// and Verifier is nil.
+	PublicKey []byte
+	// Verifier checks the asset downloaded per SignatureAsset. Defaults to
+	// Ed25519Verifier{PublicKey}.
+	Verifier Verifier
+	// Extract selects a single file out of a matched asset when it is an
+	// archive (zip or tar.gz, detected automatically). Leave nil when
+	// assets are bare binaries.
+	Extract Extract
+	// IncludePrereleases allows selecting a release marked as a prerelease.
+	IncludePrereleases bool
+	// IncludeDrafts allows selecting a draft release. Requires an
+	// authenticated Token, since drafts aren't visible anonymously.
+	IncludeDrafts bool
+	// TagPattern, if set, restricts candidate releases to tags matching
+	// this regexp, e.g. `^v1\.\d+\.\d+$` to pin to a 1.x line.
+	TagPattern string
+	// MinVersion rejects any release older than this semver, guarding
+	// against downgrades from an accidentally-published bad release.
+	MinVersion string
+	// Branch restricts workflow run listing to this branch; defaults to
+	// "main". Only used when Artifact is true.
+	Branch string
+	// WorkflowName, if set, restricts workflow run listing to the workflow
+	// with this file name (e.g. "build.yml"), resolved via
+	// Actions.GetWorkflowByFileName. Falls back to matching WorkflowRun.Name
+	// client-side if the workflow can't be resolved. Only used when
+	// Artifact is true.
+	WorkflowName string
+	// Event, if set, restricts workflow run listing to runs triggered by
+	// this event (e.g. "push", "workflow_dispatch"). Only used when
+	// Artifact is true.
+	Event string
 	// internal state
 	delay         bool
 	latestRelease time.Time
 	latestRun     int64
+	rateRemaining int
+	tagPattern    *regexp.Regexp
+	minVersion    *semver
 	githubClient  *github.Client
 	httpClient    *http.Client
 }
 
-func (h *Github) defaultAsset(filename string) bool {
-	return strings.Contains(filename, runtime.GOOS) && strings.Contains(filename, runtime.GOARCH)
-}
+var _ Fetcher = (*Github)(nil)
 
 // Init validates the provided config
 func (h *Github) Init() error {
@@ -52,7 +111,7 @@ func (h *Github) Init() error {
 		return errors.New("repo required")
 	}
 	if h.Match == nil {
-		h.Match = h.defaultAsset
+		h.Match = DefaultAsset
 	}
 
 	if h.Interval < time.Minute {
@@ -63,6 +122,38 @@ func (h *Github) Init() error {
 		h.Context = context.Background()
 	}
 
+	if h.Cache == nil {
+		h.Cache = NewMemoryCache()
+	}
+
+	if h.TagPattern != "" {
+		re, err := regexp.Compile(h.TagPattern)
+		if err != nil {
+			return fmt.Errorf("invalid TagPattern: %w", err)
+		}
+		h.tagPattern = re
+	}
+
+	if h.MinVersion != "" {
+		v, err := parseSemver(h.MinVersion)
+		if err != nil {
+			return fmt.Errorf("invalid MinVersion: %w", err)
+		}
+		h.minVersion = &v
+	}
+
+	if h.IncludeDrafts && h.Token == "" {
+		return errors.New("IncludeDrafts requires an authenticated Token")
+	}
+
+	if h.SignatureAsset != nil && h.Verifier == nil && len(h.PublicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("PublicKey must be %d bytes for the default Ed25519Verifier (got %d)", ed25519.PublicKeySize, len(h.PublicKey))
+	}
+
+	if h.Branch == "" {
+		h.Branch = "main"
+	}
+
 	h.httpClient = &http.Client{Timeout: time.Minute}
 	h.githubClient = github.NewClient(h.httpClient).WithAuthToken(h.Token)
 	return nil
@@ -77,17 +168,108 @@ func (h *Github) Fetch() (io.Reader, error) {
 
 	h.delay = true
 
+	var reader io.Reader
+	var err error
 	if h.Artifact {
-		return h.fetchLatestArtifact()
+		reader, err = h.fetchLatestArtifact()
+	} else {
+		reader, err = h.fetchLatestRelease()
 	}
-	return h.fetchLatestRelease()
+
+	if err != nil {
+		if retryAfter, ok := h.rateLimitDelay(err); ok {
+			if h.RateLimitCallback != nil {
+				h.RateLimitCallback(retryAfter)
+			}
+			time.Sleep(retryAfter)
+			return nil, ErrRateLimited
+		}
+		return nil, err
+	}
+
+	return reader, nil
 }
 
-func (h *Github) fetchLatestRelease() (io.Reader, error) {
-	release, resp, err := h.githubClient.Repositories.GetLatestRelease(h.Context, h.User, h.Repo)
-	if resp.Body != nil {
+// rateLimitDelay inspects err for a *github.RateLimitError or
+// *github.AbuseRateLimitError and returns how long to wait before the next
+// attempt. The returned duration is capped at MaxBackoff when it is set.
+func (h *Github) rateLimitDelay(err error) (time.Duration, bool) {
+	var retryAfter time.Duration
+
+	var rateErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	switch {
+	case errors.As(err, &rateErr):
+		retryAfter = time.Until(rateErr.Rate.Reset.Time)
+	case errors.As(err, &abuseErr):
+		if abuseErr.RetryAfter != nil {
+			retryAfter = *abuseErr.RetryAfter
+		}
+	default:
+		return 0, false
+	}
+
+	if retryAfter < 0 {
+		retryAfter = 0
+	}
+	if h.MaxBackoff > 0 && retryAfter > h.MaxBackoff {
+		retryAfter = h.MaxBackoff
+	}
+	return retryAfter, true
+}
+
+// recordRate captures the remaining quota from resp. It does not act on the
+// value itself; callers that want to back off as the quota runs low should
+// poll RateRemaining and lengthen Interval themselves.
+func (h *Github) recordRate(resp *github.Response) {
+	if resp == nil {
+		return
+	}
+	h.rateRemaining = resp.Rate.Remaining
+}
+
+// RateRemaining returns the X-RateLimit-Remaining value from the most recent
+// response, or 0 if no request has completed yet. Callers can use this to
+// proactively lengthen Interval before the rate limiter trips.
+func (h *Github) RateRemaining() int {
+	return h.rateRemaining
+}
+
+// channelConfigured reports whether any channel option steers Fetch away
+// from the plain "latest" release.
+func (h *Github) channelConfigured() bool {
+	return h.IncludePrereleases || h.IncludeDrafts || h.tagPattern != nil || h.MinVersion != ""
+}
+
+// resolveRelease returns the release Fetch should consider: the plain
+// "latest" release by default, or the newest release satisfying the
+// configured channel when one is set.
+func (h *Github) resolveRelease() (*github.RepositoryRelease, error) {
+	if h.channelConfigured() {
+		return h.resolveChannelRelease()
+	}
+	return h.resolveDefaultRelease()
+}
+
+func (h *Github) resolveDefaultRelease() (*github.RepositoryRelease, error) {
+	const cacheKey = "release"
+
+	req, err := h.githubClient.NewRequest(http.MethodGet, fmt.Sprintf("repos/%s/%s/releases/latest", h.User, h.Repo), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	if entry, ok := h.Cache.Load(cacheKey); ok && entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+
+	var release github.RepositoryRelease
+	resp, err := h.githubClient.Do(h.Context, req, &release)
+	if resp != nil && resp.Body != nil {
 		defer resp.Body.Close()
 	}
+	if resp != nil && resp.StatusCode == http.StatusNotModified {
+		return nil, errors.New("no new release")
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get last release: %w", err)
 	}
@@ -95,6 +277,55 @@ func (h *Github) fetchLatestRelease() (io.Reader, error) {
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("failed to get last release: %s", resp.Status)
 	}
+	h.recordRate(resp)
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		if err := h.Cache.Save(cacheKey, CacheEntry{ETag: etag}); err != nil {
+			return nil, fmt.Errorf("failed to save cache: %w", err)
+		}
+	}
+	return &release, nil
+}
+
+func (h *Github) resolveChannelRelease() (*github.RepositoryRelease, error) {
+	const cacheKey = "releases"
+
+	req, err := h.githubClient.NewRequest(http.MethodGet, fmt.Sprintf("repos/%s/%s/releases?per_page=30", h.User, h.Repo), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	if entry, ok := h.Cache.Load(cacheKey); ok && entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+
+	var releases []*github.RepositoryRelease
+	resp, err := h.githubClient.Do(h.Context, req, &releases)
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	if resp != nil && resp.StatusCode == http.StatusNotModified {
+		return nil, errors.New("no new release")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list releases: %s", resp.Status)
+	}
+	h.recordRate(resp)
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		if err := h.Cache.Save(cacheKey, CacheEntry{ETag: etag}); err != nil {
+			return nil, fmt.Errorf("failed to save cache: %w", err)
+		}
+	}
+
+	return h.selectChannelRelease(releases)
+}
+
+func (h *Github) fetchLatestRelease() (io.Reader, error) {
+	release, err := h.resolveRelease()
+	if err != nil {
+		return nil, err
+	}
 
 	for _, asset := range release.Assets {
 		if h.Match(asset.GetName()) {
@@ -106,35 +337,196 @@ func (h *Github) fetchLatestRelease() (io.Reader, error) {
 				return nil, fmt.Errorf("failed to download release asset: %w", err)
 			}
 			h.latestRelease = asset.UpdatedAt.Time
-			return body, nil
+			if h.ChecksumAsset == nil && h.SignatureAsset == nil && h.Extract == nil {
+				return body, nil
+			}
+			return h.processAsset(release.Assets, asset.GetName(), body)
 		}
 	}
 	return nil, nil
 }
 
+// processAsset reads body fully, verifies it against a companion checksums
+// file and/or detached signature asset when configured, then hands it off
+// to Extract (if set) to pull a single file out of an archive.
+func (h *Github) processAsset(assets []*github.ReleaseAsset, filename string, body io.ReadCloser) (io.Reader, error) {
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read release asset: %w", err)
+	}
+
+	if h.ChecksumAsset != nil {
+		checksums, err := h.downloadCompanionAsset(assets, h.ChecksumAsset)
+		if err != nil {
+			return nil, err
+		}
+		if err := verifyChecksum(checksums, filename, data); err != nil {
+			return nil, err
+		}
+	}
+
+	if h.SignatureAsset != nil {
+		signature, err := h.downloadCompanionAsset(assets, h.SignatureAsset)
+		if err != nil {
+			return nil, err
+		}
+		verifier := h.Verifier
+		if verifier == nil {
+			verifier = Ed25519Verifier{PublicKey: ed25519.PublicKey(h.PublicKey)}
+		}
+		if err := verifier.Verify(data, signature); err != nil {
+			return nil, err
+		}
+	}
+
+	if h.Extract == nil {
+		return bytes.NewReader(data), nil
+	}
+	files, err := openArchive(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	return h.Extract(files)
+}
+
+// downloadCompanionAsset finds the first release asset matched by match and
+// returns its full contents.
+func (h *Github) downloadCompanionAsset(assets []*github.ReleaseAsset, match func(string) bool) ([]byte, error) {
+	for _, asset := range assets {
+		if match(asset.GetName()) {
+			body, _, err := h.githubClient.Repositories.DownloadReleaseAsset(h.Context, h.User, h.Repo, asset.GetID(), h.httpClient)
+			if err != nil {
+				return nil, fmt.Errorf("failed to download %s: %w", asset.GetName(), err)
+			}
+			defer body.Close()
+			data, err := io.ReadAll(body)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", asset.GetName(), err)
+			}
+			return data, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no companion asset found", ErrVerificationFailed)
+}
+
+// matchWorkflowRun returns the first run in runs, filtering by WorkflowName
+// only when scoped is false (i.e. resolveWorkflowID couldn't scope the
+// listing server-side). WorkflowName is a file name (e.g. "build.yml"), so
+// it's compared against the base name of WorkflowRun.Path, not
+// WorkflowRun.Name (the workflow's display name, e.g. "CI"). Returns nil if
+// none match.
+func (h *Github) matchWorkflowRun(runs []*github.WorkflowRun, scoped bool) *github.WorkflowRun {
+	for _, run := range runs {
+		if !scoped && h.WorkflowName != "" && path.Base(run.GetPath()) != h.WorkflowName {
+			continue
+		}
+		return run
+	}
+	return nil
+}
+
+// resolveWorkflowID looks up the numeric workflow ID for WorkflowName, so
+// the run listing can be scoped to just that workflow's file. Returns
+// ok=false when WorkflowName is unset or unknown, in which case callers
+// should fall back to matching WorkflowRun.Name client-side.
+func (h *Github) resolveWorkflowID() (int64, bool) {
+	if h.WorkflowName == "" {
+		return 0, false
+	}
+	workflow, resp, err := h.githubClient.Actions.GetWorkflowByFileName(h.Context, h.User, h.Repo, h.WorkflowName)
+	if resp != nil && resp.Body != nil {
+		resp.Body.Close()
+	}
+	if err != nil {
+		return 0, false
+	}
+	return workflow.GetID(), true
+}
+
+// checkRunAncestry rejects a run whose headSHA is no longer reachable from
+// branchHead, which happens when the branch was force-pushed over the
+// run's history. A run that is simply behind the current tip (ordinary
+// commits landed after CI started) is not stale and must be allowed
+// through, so this only fails on "diverged" or "behind" comparisons, not
+// "identical" or "ahead".
+func (h *Github) checkRunAncestry(headSHA, branchHead string) error {
+	if headSHA == branchHead {
+		return nil
+	}
+	comparison, _, err := h.githubClient.Repositories.CompareCommits(h.Context, h.User, h.Repo, headSHA, branchHead, nil)
+	if err != nil {
+		return fmt.Errorf("failed to compare run head with branch: %w", err)
+	}
+	switch comparison.GetStatus() {
+	case "identical", "ahead":
+		return nil
+	default:
+		return fmt.Errorf("stale workflow run: head %s is no longer an ancestor of %s HEAD %s (status %s)", headSHA, h.Branch, branchHead, comparison.GetStatus())
+	}
+}
+
 func (h *Github) fetchLatestArtifact() (io.Reader, error) {
-	runs, resp, err := h.githubClient.Actions.ListRepositoryWorkflowRuns(h.Context, h.User, h.Repo, &github.ListWorkflowRunsOptions{
-		Branch: "main",
-		Status: "success",
-		ListOptions: github.ListOptions{
-			PerPage: 1,
-		},
-	})
+	const cacheKey = "runs"
+
+	endpoint := fmt.Sprintf("repos/%s/%s/actions/runs", h.User, h.Repo)
+	workflowID, scoped := h.resolveWorkflowID()
+	if scoped {
+		endpoint = fmt.Sprintf("repos/%s/%s/actions/workflows/%d/runs", h.User, h.Repo, workflowID)
+	}
+
+	query := url.Values{
+		"branch":   {h.Branch},
+		"status":   {"success"},
+		"per_page": {"10"},
+	}
+	if h.Event != "" {
+		query.Set("event", h.Event)
+	}
+
+	req, err := h.githubClient.NewRequest(http.MethodGet, endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	if entry, ok := h.Cache.Load(cacheKey); ok && entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+
+	var runs github.WorkflowRuns
+	resp, err := h.githubClient.Do(h.Context, req, &runs)
 	if resp != nil && resp.Body != nil {
 		defer resp.Body.Close()
 	}
+	if resp != nil && resp.StatusCode == http.StatusNotModified {
+		return nil, errors.New("no new run")
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get workflow runs: %w", err)
 	}
-	if len(runs.WorkflowRuns) == 0 {
-		return nil, errors.New("no successful workflow runs")
+	h.recordRate(resp)
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		if err := h.Cache.Save(cacheKey, CacheEntry{ETag: etag}); err != nil {
+			return nil, fmt.Errorf("failed to save cache: %w", err)
+		}
 	}
 
-	if h.latestRun == runs.WorkflowRuns[0].GetID() {
+	run := h.matchWorkflowRun(runs.WorkflowRuns, scoped)
+	if run == nil {
+		return nil, errors.New("no successful workflow runs")
+	}
+	if h.latestRun == run.GetID() {
 		return nil, errors.New("no new run")
 	}
 
-	artifacts, resp, err := h.githubClient.Actions.ListWorkflowRunArtifacts(h.Context, h.User, h.Repo, runs.WorkflowRuns[0].GetID(), nil)
+	branch, _, err := h.githubClient.Repositories.GetBranch(h.Context, h.User, h.Repo, h.Branch, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branch head: %w", err)
+	}
+	if err := h.checkRunAncestry(run.GetHeadSHA(), branch.GetCommit().GetSHA()); err != nil {
+		return nil, err
+	}
+
+	artifacts, resp, err := h.githubClient.Actions.ListWorkflowRunArtifacts(h.Context, h.User, h.Repo, run.GetID(), nil)
 	if resp.Body != nil {
 		defer resp.Body.Close()
 	}
@@ -170,7 +562,7 @@ func (h *Github) fetchLatestArtifact() (io.Reader, error) {
 				return nil, fmt.Errorf("failed to download artifact: %w", err)
 			}
 
-			h.latestRun = runs.WorkflowRuns[0].GetID()
+			h.latestRun = run.GetID()
 			body, err := io.ReadAll(urlResp.Body)
 			if err != nil {
 				return nil, fmt.Errorf("failed to read artifact body: %w", err)
@@ -182,6 +574,9 @@ func (h *Github) fetchLatestArtifact() (io.Reader, error) {
 			if len(reader.File) == 0 {
 				return nil, errors.New("no files in archive")
 			}
+			if h.Extract != nil {
+				return h.Extract(archiveFilesFromZip(reader))
+			}
 			return reader.File[0].Open()
 		}
 	}
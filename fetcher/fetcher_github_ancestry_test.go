@@ -0,0 +1,63 @@
+package fetcher
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestCheckRunAncestry(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		wantErr bool
+	}{
+		{"identical is not stale", "identical", false},
+		{"ahead is not stale", "ahead", false},
+		{"behind is stale", "behind", true},
+		{"diverged is stale", "diverged", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/repos/owner/repo/compare/headsha...branchhead", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"status": %q}`, tt.status)
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			h := &Github{User: "owner", Repo: "repo", Branch: "main"}
+			if err := h.Init(); err != nil {
+				t.Fatalf("Init() failed: %v", err)
+			}
+			base, err := url.Parse(server.URL + "/")
+			if err != nil {
+				t.Fatalf("failed to parse test server URL: %v", err)
+			}
+			h.githubClient.BaseURL = base
+
+			err = h.checkRunAncestry("headsha", "branchhead")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkRunAncestry() with status %q error = %v, wantErr %v", tt.status, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckRunAncestryExactMatchSkipsCompare(t *testing.T) {
+	h := &Github{User: "owner", Repo: "repo", Branch: "main"}
+	if err := h.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	// githubClient.BaseURL is left pointing at the real API; if
+	// checkRunAncestry called CompareCommits for equal SHAs it would try to
+	// reach the network and this test would hang/fail instead of passing
+	// immediately.
+	if err := h.checkRunAncestry("samesha", "samesha"); err != nil {
+		t.Errorf("checkRunAncestry() with equal SHAs returned error: %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package fetcher
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func newTestGithub(t *testing.T, server *httptest.Server) *Github {
+	t.Helper()
+	h := &Github{User: "owner", Repo: "repo"}
+	if err := h.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	base, err := url.Parse(server.URL + "/")
+	if err != nil {
+		t.Fatalf("failed to parse test server URL: %v", err)
+	}
+	h.githubClient.BaseURL = base
+	h.httpClient = server.Client()
+	return h
+}
+
+func TestResolveDefaultReleaseETagShortCircuit(t *testing.T) {
+	var requests int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"tag_name": "v1.0.0"}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	h := newTestGithub(t, server)
+
+	release, err := h.resolveDefaultRelease()
+	if err != nil {
+		t.Fatalf("resolveDefaultRelease() first call failed: %v", err)
+	}
+	if release.GetTagName() != "v1.0.0" {
+		t.Fatalf("resolveDefaultRelease() tag = %s, want v1.0.0", release.GetTagName())
+	}
+
+	if _, err := h.resolveDefaultRelease(); err == nil {
+		t.Error("resolveDefaultRelease() on a 304 should error (no new release)")
+	}
+
+	if requests != 2 {
+		t.Fatalf("expected 2 requests to reach the server, got %d", requests)
+	}
+}
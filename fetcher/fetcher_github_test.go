@@ -0,0 +1,93 @@
+package fetcher
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v64/github"
+)
+
+func TestRateLimitDelay(t *testing.T) {
+	future := time.Now().Add(2 * time.Minute)
+	retryAfter := 30 * time.Second
+
+	tests := []struct {
+		name        string
+		err         error
+		maxBackoff  time.Duration
+		wantOK      bool
+		wantAtLeast time.Duration
+		wantAtMost  time.Duration
+	}{
+		{
+			name:        "rate limit error uses reset time",
+			err:         &github.RateLimitError{Rate: github.Rate{Reset: github.Timestamp{Time: future}}},
+			wantOK:      true,
+			wantAtLeast: 0,
+			wantAtMost:  2 * time.Minute,
+		},
+		{
+			name:        "rate limit error capped at MaxBackoff",
+			err:         &github.RateLimitError{Rate: github.Rate{Reset: github.Timestamp{Time: future}}},
+			maxBackoff:  10 * time.Second,
+			wantOK:      true,
+			wantAtLeast: 10 * time.Second,
+			wantAtMost:  10 * time.Second,
+		},
+		{
+			name:        "abuse rate limit error uses RetryAfter",
+			err:         &github.AbuseRateLimitError{RetryAfter: &retryAfter},
+			wantOK:      true,
+			wantAtLeast: retryAfter,
+			wantAtMost:  retryAfter,
+		},
+		{
+			name:   "abuse rate limit error without RetryAfter waits zero",
+			err:    &github.AbuseRateLimitError{},
+			wantOK: true,
+		},
+		{
+			name:   "unrelated error is not a rate limit",
+			err:    errors.New("boom"),
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Github{MaxBackoff: tt.maxBackoff}
+			got, ok := h.rateLimitDelay(tt.err)
+			if ok != tt.wantOK {
+				t.Fatalf("rateLimitDelay() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got < tt.wantAtLeast || got > tt.wantAtMost {
+				t.Errorf("rateLimitDelay() = %v, want between %v and %v", got, tt.wantAtLeast, tt.wantAtMost)
+			}
+		})
+	}
+}
+
+func TestRecordRateAndRateRemaining(t *testing.T) {
+	h := &Github{Interval: time.Minute}
+
+	if got := h.RateRemaining(); got != 0 {
+		t.Fatalf("RateRemaining() before any response = %d, want 0", got)
+	}
+
+	h.recordRate(&github.Response{Rate: github.Rate{Remaining: 5}})
+	if got := h.RateRemaining(); got != 5 {
+		t.Errorf("RateRemaining() = %d, want 5", got)
+	}
+	if h.Interval != time.Minute {
+		t.Errorf("recordRate() must not mutate Interval, got %v", h.Interval)
+	}
+
+	h.recordRate(nil)
+	if got := h.RateRemaining(); got != 5 {
+		t.Errorf("RateRemaining() after nil response = %d, want unchanged 5", got)
+	}
+}
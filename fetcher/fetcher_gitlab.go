@@ -0,0 +1,147 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var _ Fetcher = (*GitLab)(nil)
+
+// GitLab uses the GitLab Releases API to retrieve the latest release of a
+// given project and enumerate its link assets. If a release contains a
+// matching asset, it will fetch and return its io.Reader stream.
+type GitLab struct {
+	// Project is the GitLab project path or ID, e.g. "group/project".
+	Project string
+	// BaseURL is the API root; defaults to https://gitlab.com/api/v4 for
+	// self-managed instances set it to e.g. https://gitlab.example.com/api/v4.
+	BaseURL string
+	// Token is optional for authenticated requests (private projects),
+	// sent as a PRIVATE-TOKEN header.
+	Token string
+	// Interval between fetches
+	Interval time.Duration
+	// Match is used to find matching release asset.
+	// By default a file will match if it contains both GOOS and GOARCH.
+	Match   func(filename string) bool
+	Context context.Context
+	// internal state
+	delay         bool
+	latestRelease time.Time
+	httpClient    *http.Client
+}
+
+type gitlabRelease struct {
+	TagName    string    `json:"tag_name"`
+	ReleasedAt time.Time `json:"released_at"`
+	Assets     struct {
+		Links []struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"links"`
+	} `json:"assets"`
+}
+
+// Init validates the provided config
+func (g *GitLab) Init() error {
+	if g.Project == "" {
+		return errors.New("project required")
+	}
+	if g.Match == nil {
+		g.Match = DefaultAsset
+	}
+	if g.BaseURL == "" {
+		g.BaseURL = "https://gitlab.com/api/v4"
+	}
+	if g.Interval < time.Minute {
+		g.Interval = time.Minute
+	}
+	if g.Context == nil {
+		g.Context = context.Background()
+	}
+	g.httpClient = &http.Client{Timeout: time.Minute}
+	return nil
+}
+
+// Fetch the binary from the provided Project
+func (g *GitLab) Fetch() (io.Reader, error) {
+	if g.delay {
+		time.Sleep(g.Interval)
+	}
+	g.delay = true
+
+	return g.fetchLatestRelease()
+}
+
+func (g *GitLab) fetchLatestRelease() (io.Reader, error) {
+	reqURL := fmt.Sprintf("%s/projects/%s/releases?order_by=released_at&sort=desc&per_page=1",
+		g.BaseURL, url.PathEscape(g.Project))
+
+	req, err := http.NewRequestWithContext(g.Context, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	if g.Token != "" {
+		req.Header.Set("PRIVATE-TOKEN", g.Token)
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list releases: %s", resp.Status)
+	}
+
+	// X-Total reports how many releases exist across all pages; per_page=1
+	// combined with order_by=released_at already gives us the newest one,
+	// so it is only used to sanity-check that the project has releases.
+	if total, err := strconv.Atoi(resp.Header.Get("X-Total")); err == nil && total == 0 {
+		return nil, errors.New("no releases found")
+	}
+
+	var releases []gitlabRelease
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("failed to decode releases: %w", err)
+	}
+	if len(releases) == 0 {
+		return nil, errors.New("no releases found")
+	}
+
+	release := releases[0]
+	if g.latestRelease.Equal(release.ReleasedAt) {
+		return nil, errors.New("no new release")
+	}
+
+	for _, link := range release.Assets.Links {
+		if g.Match(link.Name) {
+			assetReq, err := http.NewRequestWithContext(g.Context, http.MethodGet, link.URL, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build asset request: %w", err)
+			}
+			if g.Token != "" {
+				assetReq.Header.Set("PRIVATE-TOKEN", g.Token)
+			}
+			assetResp, err := g.httpClient.Do(assetReq)
+			if err != nil {
+				return nil, fmt.Errorf("failed to download release asset: %w", err)
+			}
+			if assetResp.StatusCode != http.StatusOK {
+				assetResp.Body.Close()
+				return nil, fmt.Errorf("failed to download release asset: %s", assetResp.Status)
+			}
+			g.latestRelease = release.ReleasedAt
+			return assetResp.Body, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,70 @@
+package fetcher
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGitLabFetchLatestRelease(t *testing.T) {
+	var assetServerURL string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/group/project/releases", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Total", "1")
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `[{
+			"tag_name": "v1.0.0",
+			"released_at": "2026-01-01T00:00:00Z",
+			"assets": {"links": [{"name": "tool_linux_amd64", "url": "`+assetServerURL+`/download"}]}
+		}]`)
+	})
+	mux.HandleFunc("/download", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "binary contents")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	assetServerURL = server.URL
+
+	g := &GitLab{
+		Project: "group/project",
+		BaseURL: server.URL,
+		Match:   func(name string) bool { return name == "tool_linux_amd64" },
+	}
+	if err := g.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	r, err := g.fetchLatestRelease()
+	if err != nil {
+		t.Fatalf("fetchLatestRelease() failed: %v", err)
+	}
+	body, _ := io.ReadAll(r)
+	if string(body) != "binary contents" {
+		t.Errorf("fetchLatestRelease() body = %q", body)
+	}
+
+	if _, err := g.fetchLatestRelease(); err == nil {
+		t.Error("fetchLatestRelease() with an unchanged release should error")
+	}
+}
+
+func TestGitLabFetchLatestReleaseNoReleases(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/group%2Fproject/releases", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Total", "0")
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `[]`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	g := &GitLab{Project: "group/project", BaseURL: server.URL}
+	if err := g.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	if _, err := g.fetchLatestRelease(); err == nil {
+		t.Error("fetchLatestRelease() with no releases should error")
+	}
+}
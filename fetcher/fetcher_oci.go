@@ -0,0 +1,363 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"time"
+)
+
+var _ Fetcher = (*OCI)(nil)
+
+// OCI pulls a binary layer out of an OCI/Docker image by digest, for shops
+// that distribute release artifacts via a registry (ghcr.io, Artifact
+// Registry, etc.) instead of Github/Gitea/GitLab releases.
+type OCI struct {
+	// Registry host, e.g. ghcr.io or us-docker.pkg.dev.
+	Registry string
+	// Repository is the image path, e.g. "owner/image".
+	Repository string
+	// Tag to resolve to a manifest digest; defaults to "latest".
+	Tag string
+	// Token is an optional static bearer token. When empty, an anonymous
+	// pull token is requested from the registry's token endpoint.
+	Token string
+	// Interval between fetches
+	Interval time.Duration
+	// Match is used to find the matching layer by its
+	// "org.opencontainers.image.title" annotation.
+	// By default a layer matches if its title contains both GOOS and GOARCH.
+	Match   func(filename string) bool
+	Context context.Context
+	// internal state
+	delay        bool
+	latestDigest string
+	httpClient   *http.Client
+}
+
+// ociManifestResponse covers both shapes the manifests endpoint can return:
+// a single-platform image manifest (Layers populated) or a multi-platform
+// image index / manifest list (Manifests populated). ghcr.io and Artifact
+// Registry serve an index for virtually all published multi-arch images.
+type ociManifestResponse struct {
+	MediaType string                  `json:"mediaType"`
+	Manifests []ociManifestDescriptor `json:"manifests,omitempty"`
+	Layers    []ociDescriptor         `json:"layers,omitempty"`
+}
+
+// ociManifestDescriptor is an entry in an image index, pointing at the
+// manifest for one platform.
+type ociManifestDescriptor struct {
+	MediaType string       `json:"mediaType"`
+	Digest    string       `json:"digest"`
+	Platform  *ociPlatform `json:"platform"`
+}
+
+type ociPlatform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+const ociLayerTitleAnnotation = "org.opencontainers.image.title"
+
+const ociAcceptManifestTypes = "application/vnd.oci.image.manifest.v1+json, application/vnd.docker.distribution.manifest.v2+json, application/vnd.oci.image.index.v1+json, application/vnd.docker.distribution.manifest.list.v2+json"
+
+// Init validates the provided config
+func (o *OCI) Init() error {
+	if o.Registry == "" {
+		return errors.New("registry required")
+	}
+	if o.Repository == "" {
+		return errors.New("repository required")
+	}
+	if o.Tag == "" {
+		o.Tag = "latest"
+	}
+	if o.Match == nil {
+		o.Match = DefaultAsset
+	}
+	if o.Interval < time.Minute {
+		o.Interval = time.Minute
+	}
+	if o.Context == nil {
+		o.Context = context.Background()
+	}
+	o.httpClient = &http.Client{Timeout: time.Minute}
+	return nil
+}
+
+// Fetch the binary layer from the provided image
+func (o *OCI) Fetch() (io.Reader, error) {
+	if o.delay {
+		time.Sleep(o.Interval)
+	}
+	o.delay = true
+
+	return o.fetchLatestLayer()
+}
+
+func (o *OCI) fetchLatestLayer() (io.Reader, error) {
+	token, err := o.resolveToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registry token: %w", err)
+	}
+
+	manifest, digest, err := o.resolveManifest(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if digest != "" && digest == o.latestDigest {
+		return nil, errors.New("no new image")
+	}
+
+	for _, layer := range manifest.Layers {
+		if o.Match(layer.Annotations[ociLayerTitleAnnotation]) {
+			blob, err := o.fetchBlob(layer.Digest, token)
+			if err != nil {
+				return nil, err
+			}
+			o.latestDigest = digest
+			return blob, nil
+		}
+	}
+	return nil, nil
+}
+
+// resolveManifest fetches the manifest for Tag and, if the registry served
+// an image index / manifest list instead of a single-platform manifest,
+// resolves it down to the manifest matching GOOS/GOARCH. The digest
+// returned is always the top-level one (the index's, when present) so
+// latestDigest tracks the image as a whole rather than one platform slice.
+func (o *OCI) resolveManifest(token string) (*ociManifestResponse, string, error) {
+	manifest, digest, err := o.fetchManifest(o.Tag, token)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(manifest.Manifests) == 0 {
+		return manifest, digest, nil
+	}
+
+	platformDigest, err := selectPlatformDigest(manifest.Manifests)
+	if err != nil {
+		return nil, "", err
+	}
+
+	platformManifest, _, err := o.fetchManifest(platformDigest, token)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get platform manifest: %w", err)
+	}
+	if len(platformManifest.Manifests) != 0 {
+		return nil, "", errors.New("nested manifest index is not supported")
+	}
+	return platformManifest, digest, nil
+}
+
+// selectPlatformDigest returns the digest of the manifest in manifests whose
+// platform matches runtime.GOOS/runtime.GOARCH.
+func selectPlatformDigest(manifests []ociManifestDescriptor) (string, error) {
+	for _, m := range manifests {
+		if m.Platform != nil && m.Platform.OS == runtime.GOOS && m.Platform.Architecture == runtime.GOARCH {
+			return m.Digest, nil
+		}
+	}
+	return "", fmt.Errorf("no manifest in index matches %s/%s", runtime.GOOS, runtime.GOARCH)
+}
+
+func (o *OCI) fetchManifest(ref, token string) (*ociManifestResponse, string, error) {
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", o.Registry, o.Repository, ref)
+	req, err := http.NewRequestWithContext(o.Context, http.MethodGet, manifestURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", ociAcceptManifestTypes)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get manifest: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("failed to get manifest: %s", resp.Status)
+	}
+
+	digest := resp.Header.Get("Docker-Content-Digest")
+	var manifest ociManifestResponse
+	if err := json.NewDecoder(resp.Body).Decode(&manifest); err != nil {
+		return nil, "", fmt.Errorf("failed to decode manifest: %w", err)
+	}
+	if len(manifest.Manifests) == 0 && len(manifest.Layers) == 0 && !strings.Contains(manifest.MediaType, "index") && !strings.Contains(manifest.MediaType, "list") {
+		return nil, "", fmt.Errorf("manifest has no layers or platform entries (mediaType %q)", manifest.MediaType)
+	}
+	return &manifest, digest, nil
+}
+
+func (o *OCI) fetchBlob(digest, token string) (io.Reader, error) {
+	blobURL := fmt.Sprintf("https://%s/v2/%s/blobs/%s", o.Registry, o.Repository, digest)
+	req, err := http.NewRequestWithContext(o.Context, http.MethodGet, blobURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build blob request: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download blob: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to download blob: %s", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// authChallenge is the parsed form of a "WWW-Authenticate: Bearer ..."
+// challenge, per the OCI/Docker Registry v2 distribution spec.
+type authChallenge struct {
+	realm, service, scope string
+}
+
+// resolveToken returns o.Token if set, otherwise discovers the registry's
+// auth endpoint by probing the manifest URL and reading the 401 response's
+// WWW-Authenticate challenge, then exchanges it for a pull token. This is
+// the real distribution-spec flow (rather than a fixed "/token" endpoint),
+// since the realm varies by registry - e.g. ghcr.io and Artifact Registry
+// use different hosts and paths for their auth service.
+func (o *OCI) resolveToken() (string, error) {
+	if o.Token != "" {
+		return o.Token, nil
+	}
+
+	challenge, err := o.probeAuthChallenge()
+	if err != nil {
+		return "", err
+	}
+	if challenge == nil {
+		// No 401 on the anonymous probe: the registry allows anonymous
+		// pulls for this repository, so no token is needed.
+		return "", nil
+	}
+	return o.fetchBearerToken(*challenge)
+}
+
+// probeAuthChallenge makes an anonymous request to the manifest endpoint and
+// returns the Bearer challenge from a 401 response, or nil if the request
+// didn't need auth. It errors if the registry returns 401 without a Bearer
+// WWW-Authenticate header, rather than silently falling back to an
+// unauthenticated request that would just fail again downstream.
+func (o *OCI) probeAuthChallenge() (*authChallenge, error) {
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", o.Registry, o.Repository, o.Tag)
+	req, err := http.NewRequestWithContext(o.Context, http.MethodGet, manifestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build auth probe request: %w", err)
+	}
+	req.Header.Set("Accept", ociAcceptManifestTypes)
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe registry auth: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		return nil, nil
+	}
+	challenge, ok := parseBearerChallenge(resp.Header.Get("WWW-Authenticate"))
+	if !ok {
+		return nil, fmt.Errorf("registry returned 401 without a Bearer WWW-Authenticate challenge")
+	}
+	return &challenge, nil
+}
+
+// parseBearerChallenge parses a "Bearer realm=\"...\",service=\"...\",scope=\"...\""
+// WWW-Authenticate header value.
+func parseBearerChallenge(header string) (authChallenge, bool) {
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return authChallenge{}, false
+	}
+	var c authChallenge
+	for _, part := range strings.Split(strings.TrimPrefix(header, prefix), ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "realm":
+			c.realm = strings.Trim(kv[1], `"`)
+		case "service":
+			c.service = strings.Trim(kv[1], `"`)
+		case "scope":
+			c.scope = strings.Trim(kv[1], `"`)
+		}
+	}
+	if c.realm == "" {
+		return authChallenge{}, false
+	}
+	return c, true
+}
+
+// fetchBearerToken exchanges an auth challenge for a pull token at its
+// realm, per the distribution spec's GET <realm>?service=...&scope=... flow.
+func (o *OCI) fetchBearerToken(c authChallenge) (string, error) {
+	tokenURL, err := url.Parse(c.realm)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse auth realm %q: %w", c.realm, err)
+	}
+	scope := c.scope
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", o.Repository)
+	}
+	q := tokenURL.Query()
+	if c.service != "" {
+		q.Set("service", c.service)
+	}
+	q.Set("scope", scope)
+	tokenURL.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(o.Context, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build token request: %w", err)
+	}
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get auth token from %s: %s", c.realm, resp.Status)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
@@ -0,0 +1,109 @@
+package fetcher
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestOCIFetchLatestLayer(t *testing.T) {
+	var tokenURL string
+	const wantBlob = "binary contents"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"token":"test-token"}`)
+	})
+	mux.HandleFunc("/v2/owner/repo/manifests/latest", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm=%q,service="test-registry",scope="repository:owner/repo:pull"`, tokenURL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Docker-Content-Digest", "sha256:indexdigest")
+		w.Header().Set("Content-Type", "application/vnd.oci.image.index.v1+json")
+		fmt.Fprintf(w, `{
+			"mediaType": "application/vnd.oci.image.index.v1+json",
+			"manifests": [
+				{"mediaType": "application/vnd.oci.image.manifest.v1+json", "digest": "sha256:otherplatform", "platform": {"os": "plan9", "architecture": "386"}},
+				{"mediaType": "application/vnd.oci.image.manifest.v1+json", "digest": "sha256:platformdigest", "platform": {"os": %q, "architecture": %q}}
+			]
+		}`, runtime.GOOS, runtime.GOARCH)
+	})
+	mux.HandleFunc("/v2/owner/repo/manifests/sha256:platformdigest", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+		fmt.Fprintf(w, `{
+			"mediaType": "application/vnd.oci.image.manifest.v1+json",
+			"layers": [{"mediaType": "application/vnd.oci.image.layer.v1.tar", "digest": "sha256:layerdigest", "annotations": {"org.opencontainers.image.title": "tool_%s_%s"}}]
+		}`, runtime.GOOS, runtime.GOARCH)
+	})
+	mux.HandleFunc("/v2/owner/repo/blobs/sha256:layerdigest", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		io.WriteString(w, wantBlob)
+	})
+
+	server := httptest.NewTLSServer(mux)
+	defer server.Close()
+	tokenURL = server.URL + "/token"
+
+	o := &OCI{
+		Registry:   strings.TrimPrefix(server.URL, "https://"),
+		Repository: "owner/repo",
+	}
+	if err := o.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	o.httpClient = server.Client()
+
+	r, err := o.fetchLatestLayer()
+	if err != nil {
+		t.Fatalf("fetchLatestLayer() failed: %v", err)
+	}
+	body, _ := io.ReadAll(r)
+	if string(body) != wantBlob {
+		t.Errorf("fetchLatestLayer() body = %q, want %q", body, wantBlob)
+	}
+
+	if _, err := o.fetchLatestLayer(); !strings.Contains(err.Error(), "no new image") {
+		t.Errorf("fetchLatestLayer() on unchanged digest = %v, want \"no new image\"", err)
+	}
+}
+
+func TestOCIFetchLatestLayerUnknownPlatform(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/owner/repo/manifests/latest", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.oci.image.index.v1+json")
+		io.WriteString(w, `{
+			"mediaType": "application/vnd.oci.image.index.v1+json",
+			"manifests": [{"mediaType": "application/vnd.oci.image.manifest.v1+json", "digest": "sha256:other", "platform": {"os": "plan9", "architecture": "386"}}]
+		}`)
+	})
+	server := httptest.NewTLSServer(mux)
+	defer server.Close()
+
+	o := &OCI{
+		Registry:   strings.TrimPrefix(server.URL, "https://"),
+		Repository: "owner/repo",
+	}
+	if err := o.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	o.httpClient = server.Client()
+
+	_, err := o.fetchLatestLayer()
+	if err == nil || !strings.Contains(err.Error(), "no manifest in index matches") {
+		t.Fatalf("fetchLatestLayer() with no matching platform = %v, want a platform-mismatch error", err)
+	}
+}
@@ -0,0 +1,14 @@
+package fetcher
+
+import (
+	"runtime"
+	"strings"
+)
+
+// DefaultAsset is the fallback Match used by every Fetcher implementation
+// when none is supplied: a file matches if its name contains both GOOS and
+// GOARCH, which is how most release tooling (goreleaser, etc.) names its
+// archives.
+func DefaultAsset(filename string) bool {
+	return strings.Contains(filename, runtime.GOOS) && strings.Contains(filename, runtime.GOARCH)
+}
@@ -0,0 +1,130 @@
+package fetcher
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestParseChecksums(t *testing.T) {
+	data := []byte(strings.Join([]string{
+		"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa  tool_linux_amd64.tar.gz",
+		"BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB  tool_darwin_arm64.tar.gz",
+		"cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc *tool_windows_amd64.zip",
+		"",
+		"not a checksum line",
+	}, "\n"))
+
+	tests := []struct {
+		name     string
+		filename string
+		want     string
+		wantOK   bool
+	}{
+		{"exact match", "tool_linux_amd64.tar.gz", "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", true},
+		{"digest lowercased", "tool_darwin_arm64.tar.gz", "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", true},
+		{"binary marker stripped", "tool_windows_amd64.zip", "cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc", true},
+		{"missing entry", "tool_freebsd_amd64.tar.gz", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseChecksums(data, tt.filename)
+			if ok != tt.wantOK {
+				t.Fatalf("parseChecksums() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("parseChecksums() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("release contents")
+	sum := sha256.Sum256(data)
+	checksums := []byte(hex.EncodeToString(sum[:]) + "  asset.bin\n")
+
+	if err := verifyChecksum(checksums, "asset.bin", data); err != nil {
+		t.Fatalf("verifyChecksum() with matching digest returned error: %v", err)
+	}
+
+	if err := verifyChecksum(checksums, "other.bin", data); err == nil {
+		t.Fatal("verifyChecksum() with no entry for filename should error")
+	}
+
+	badChecksums := []byte(strings.Repeat("f", 64) + "  asset.bin\n")
+	if err := verifyChecksum(badChecksums, "asset.bin", data); err == nil {
+		t.Fatal("verifyChecksum() with mismatched digest should error")
+	}
+}
+
+func TestParseMinisignSignature(t *testing.T) {
+	keyID := strings.Repeat("\x01", 8)
+	sig := strings.Repeat("\x02", 64)
+	valid := base64.StdEncoding.EncodeToString([]byte("Ed" + keyID + sig))
+
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr bool
+	}{
+		{
+			name:    "valid signature",
+			raw:     "untrusted comment: signature from minisign\n" + valid + "\n",
+			wantErr: false,
+		},
+		{
+			name:    "too few lines",
+			raw:     valid,
+			wantErr: true,
+		},
+		{
+			name:    "not base64",
+			raw:     "untrusted comment\n!!!not-base64!!!\n",
+			wantErr: true,
+		},
+		{
+			name:    "wrong length",
+			raw:     "untrusted comment\n" + base64.StdEncoding.EncodeToString([]byte("too short")) + "\n",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported algorithm",
+			raw:     "untrusted comment\n" + base64.StdEncoding.EncodeToString([]byte("XX"+keyID+sig)) + "\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMinisignSignature([]byte(tt.raw))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseMinisignSignature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && string(got) != sig {
+				t.Errorf("parseMinisignSignature() = %q, want %q", got, sig)
+			}
+		})
+	}
+}
+
+func TestEd25519VerifierRoundTrip(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	data := []byte("asset bytes")
+	sig := ed25519.Sign(priv, data)
+
+	v := Ed25519Verifier{PublicKey: pub}
+	if err := v.Verify(data, sig); err != nil {
+		t.Errorf("Verify() with valid signature returned error: %v", err)
+	}
+	if err := v.Verify([]byte("tampered"), sig); err == nil {
+		t.Error("Verify() with tampered data should return an error")
+	}
+}